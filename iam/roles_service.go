@@ -169,3 +169,118 @@ func (p *RolesService) AddRolePermission(role Role, permission string) (RoleResp
 func (p *RolesService) RemoveRolePermission(role Role, permission string) (RoleResponse, *Response, error) {
 	return p.rolePermissionAction(role, []string{permission}, "$remove-permission")
 }
+
+// BulkAssignPermissions assigns all given permissions to the Role in a
+// single $assign-permission call
+func (p *RolesService) BulkAssignPermissions(role Role, permissions []string) (RoleResponse, *Response, error) {
+	return p.rolePermissionAction(role, permissions, "$assign-permission")
+}
+
+// BulkRemovePermissions removes all given permissions from the Role in a
+// single $remove-permission call
+func (p *RolesService) BulkRemovePermissions(role Role, permissions []string) (RoleResponse, *Response, error) {
+	return p.rolePermissionAction(role, permissions, "$remove-permission")
+}
+
+// diffPermissions computes the set difference between current and desired:
+// added holds permissions present in desired but not current, removed holds
+// permissions present in current but not desired.
+func diffPermissions(current, desired []string) (added, removed []string) {
+	currentSet := make(map[string]bool, len(current))
+	for _, permission := range current {
+		currentSet[permission] = true
+	}
+	desiredSet := make(map[string]bool, len(desired))
+	for _, permission := range desired {
+		desiredSet[permission] = true
+	}
+
+	for _, permission := range desired {
+		if !currentSet[permission] {
+			added = append(added, permission)
+		}
+	}
+	for _, permission := range current {
+		if !desiredSet[permission] {
+			removed = append(removed, permission)
+		}
+	}
+	return added, removed
+}
+
+// rejectedPermissions looks for a "rejectedPermissions" field in a
+// $assign-permission/$remove-permission response, which SyncRolePermissions
+// treats as the list of requested names the action could not apply. This
+// field name is NOT confirmed against a documented IAM response schema or
+// an existing client (Terraform provider, etc.) that parses one — it is the
+// best guess available in this tree, and returns nil (i.e. "treat the whole
+// call as applied") for any response that doesn't happen to use it,
+// including a real partial-success response shaped differently. Verify the
+// actual field name against the IAM API before relying on this for
+// anything that needs the distinction to be correct.
+func rejectedPermissions(response RoleResponse) []string {
+	raw, ok := response["rejectedPermissions"].([]interface{})
+	if !ok {
+		return nil
+	}
+	rejected := make([]string, 0, len(raw))
+	for _, entry := range raw {
+		if name, ok := entry.(string); ok {
+			rejected = append(rejected, name)
+		}
+	}
+	return rejected
+}
+
+// applied returns requested with any names in rejected removed, preserving
+// the original order.
+func applied(requested, rejected []string) []string {
+	if len(rejected) == 0 {
+		return requested
+	}
+	rejectedSet := make(map[string]bool, len(rejected))
+	for _, name := range rejected {
+		rejectedSet[name] = true
+	}
+	var result []string
+	for _, name := range requested {
+		if !rejectedSet[name] {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// SyncRolePermissions reconciles the Role's permissions with desired,
+// fetching the current set via GetRolePermissions and issuing at most one
+// BulkAssignPermissions call for additions and one BulkRemovePermissions
+// call for removals. added/removed are filtered through rejectedPermissions
+// so a recognized partial-success response excludes the names the server
+// didn't apply — see rejectedPermissions for the caveat that its field name
+// is an unverified guess, not a confirmed IAM contract.
+func (p *RolesService) SyncRolePermissions(role Role, desired []string) (added, removed []string, resp *Response, err error) {
+	current, resp, err := p.GetRolePermissions(role)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+
+	wantAdded, wantRemoved := diffPermissions(*current, desired)
+
+	if len(wantAdded) > 0 {
+		var assignResponse RoleResponse
+		assignResponse, resp, err = p.BulkAssignPermissions(role, wantAdded)
+		if err != nil {
+			return nil, nil, resp, err
+		}
+		added = applied(wantAdded, rejectedPermissions(assignResponse))
+	}
+	if len(wantRemoved) > 0 {
+		var removeResponse RoleResponse
+		removeResponse, resp, err = p.BulkRemovePermissions(role, wantRemoved)
+		if err != nil {
+			return added, nil, resp, err
+		}
+		removed = applied(wantRemoved, rejectedPermissions(removeResponse))
+	}
+	return added, removed, resp, nil
+}
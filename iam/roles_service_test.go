@@ -0,0 +1,104 @@
+package iam
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffPermissions(t *testing.T) {
+	added, removed := diffPermissions(
+		[]string{"PERM.A", "PERM.B", "PERM.C"},
+		[]string{"PERM.B", "PERM.C", "PERM.D"},
+	)
+	assert.ElementsMatch(t, []string{"PERM.D"}, added)
+	assert.ElementsMatch(t, []string{"PERM.A"}, removed)
+}
+
+func TestDiffPermissionsNoChange(t *testing.T) {
+	added, removed := diffPermissions(
+		[]string{"PERM.A", "PERM.B"},
+		[]string{"PERM.B", "PERM.A"},
+	)
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+}
+
+func TestRejectedPermissions(t *testing.T) {
+	response := RoleResponse{
+		"rejectedPermissions": []interface{}{"PERM.UNKNOWN"},
+	}
+	assert.Equal(t, []string{"PERM.UNKNOWN"}, rejectedPermissions(response))
+}
+
+func TestRejectedPermissionsAbsent(t *testing.T) {
+	response := RoleResponse{"responseCode": "ok"}
+	assert.Nil(t, rejectedPermissions(response))
+}
+
+func TestAppliedFiltersRejected(t *testing.T) {
+	result := applied([]string{"PERM.A", "PERM.B", "PERM.C"}, []string{"PERM.B"})
+	assert.Equal(t, []string{"PERM.A", "PERM.C"}, result)
+}
+
+func TestAppliedNoneRejected(t *testing.T) {
+	requested := []string{"PERM.A", "PERM.B"}
+	assert.Equal(t, requested, applied(requested, nil))
+}
+
+// TestSyncRolePermissionsRoundTrip exercises SyncRolePermissions end to end
+// against a mock IDM server, following this repo's setup/mux test pattern
+// (see cartel/create_test.go): GetRolePermissions, then one
+// $assign-permission call for the addition and one $remove-permission call
+// for the removal, with the removal reporting PERM.A as rejected.
+func TestSyncRolePermissionsRoundTrip(t *testing.T) {
+	teardown, err := setup(t, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	role := Role{ID: "role-1"}
+	svc := &RolesService{client: client}
+
+	muxIDM.HandleFunc("/authorize/identity/Permission", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "role-1", r.URL.Query().Get("roleId"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total":2,"entry":[{"name":"PERM.A"},{"name":"PERM.B"}]}`))
+	})
+	muxIDM.HandleFunc("/authorize/identity/Role/role-1/$assign-permission", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		var body struct {
+			Permissions []string `json:"permissions"`
+		}
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&body)) {
+			return
+		}
+		assert.Equal(t, []string{"PERM.C"}, body.Permissions)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"responseCode":"ok"}`))
+	})
+	muxIDM.HandleFunc("/authorize/identity/Role/role-1/$remove-permission", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		var body struct {
+			Permissions []string `json:"permissions"`
+		}
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&body)) {
+			return
+		}
+		assert.Equal(t, []string{"PERM.A"}, body.Permissions)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"rejectedPermissions":["PERM.A"]}`))
+	})
+
+	added, removed, resp, err := svc.SyncRolePermissions(role, []string{"PERM.B", "PERM.C"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotNil(t, resp)
+	assert.Equal(t, []string{"PERM.C"}, added)
+	assert.Empty(t, removed, "PERM.A was reported rejected by the server, so nothing was actually removed")
+}
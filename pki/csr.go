@@ -0,0 +1,155 @@
+package pki
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// ErrUnsupportedKeyType is returned when GenerateCSR is asked to create a
+// key of a type it does not know how to generate
+var ErrUnsupportedKeyType = fmt.Errorf("unsupported key type")
+
+// ErrUnsupportedKeyBits is returned when GenerateCSR is asked to create a
+// key with a bit size or curve it does not support for the given key type
+var ErrUnsupportedKeyBits = fmt.Errorf("unsupported key bits for key type")
+
+// GenerateCSR creates a private key of the given keyType ("rsa", "ec" or
+// "ed25519") and keyBits (2048/3072/4096 for rsa, 256/384/521 for ec, ignored
+// for ed25519), and builds a PEM-encoded PKCS#10 certificate signing request
+// from req. Both the CSR and the PKCS#8 private key are returned PEM encoded
+// so the private key never has to leave the caller.
+func GenerateCSR(req CertificateRequest, keyType string, keyBits int) (csrPEM []byte, keyPEM []byte, err error) {
+	signer, publicKeyAlgorithm, err := generateKey(keyType, keyBits)
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName: req.CommonName,
+		},
+		SignatureAlgorithm: publicKeyAlgorithm,
+	}
+	if req.ExcludeCNFromSANS == nil || !*req.ExcludeCNFromSANS {
+		template.DNSNames = append(template.DNSNames, req.CommonName)
+	}
+	for _, name := range splitAndTrim(req.AltNames) {
+		template.DNSNames = append(template.DNSNames, name)
+	}
+	for _, ip := range splitAndTrim(req.IPSANS) {
+		parsed := net.ParseIP(ip)
+		if parsed == nil {
+			return nil, nil, fmt.Errorf("parse ip_sans: invalid IP address %q", ip)
+		}
+		template.IPAddresses = append(template.IPAddresses, parsed)
+	}
+	for _, uri := range splitAndTrim(req.URISANS) {
+		parsed, err := url.Parse(uri)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse uri_sans: %w", err)
+		}
+		template.URIs = append(template.URIs, parsed)
+	}
+
+	derBytes, err := x509.CreateCertificateRequest(rand.Reader, template, signer)
+	if err != nil {
+		return nil, nil, err
+	}
+	csrPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: derBytes})
+
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(signer)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes})
+	return csrPEM, keyPEM, nil
+}
+
+func generateKey(keyType string, keyBits int) (interface{}, x509.SignatureAlgorithm, error) {
+	switch keyType {
+	case "rsa":
+		switch keyBits {
+		case 0:
+			keyBits = 2048
+		case 2048, 3072, 4096:
+		default:
+			return nil, x509.UnknownSignatureAlgorithm, ErrUnsupportedKeyBits
+		}
+		key, err := rsa.GenerateKey(rand.Reader, keyBits)
+		if err != nil {
+			return nil, x509.UnknownSignatureAlgorithm, err
+		}
+		return key, x509.SHA256WithRSA, nil
+	case "ec":
+		var curve elliptic.Curve
+		switch keyBits {
+		case 0, 256:
+			curve = elliptic.P256()
+		case 384:
+			curve = elliptic.P384()
+		case 521:
+			curve = elliptic.P521()
+		default:
+			return nil, x509.UnknownSignatureAlgorithm, ErrUnsupportedKeyBits
+		}
+		key, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			return nil, x509.UnknownSignatureAlgorithm, err
+		}
+		return key, x509.ECDSAWithSHA256, nil
+	case "ed25519":
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, x509.UnknownSignatureAlgorithm, err
+		}
+		return key, x509.PureEd25519, nil
+	}
+	return nil, x509.UnknownSignatureAlgorithm, ErrUnsupportedKeyType
+}
+
+func splitAndTrim(value string) []string {
+	if value == "" {
+		return nil
+	}
+	var out []string
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// SignCSR signs the given PEM-encoded CSR using the named role, populating
+// the remaining SignRequest fields from req. This lets a caller keep a
+// private key generated with GenerateCSR (or any other tool) on the
+// requesting host and only send the public CSR to the CA.
+func (c *ServicesService) SignCSR(logicalPath, roleName string, csrPEM []byte, req CertificateRequest, options ...OptionFunc) (*IssueResponse, *Response, error) {
+	signRequest := SignRequest{
+		CSR:        string(csrPEM),
+		CommonName: req.CommonName,
+		AltNames:   req.AltNames,
+		OtherSans:  req.OtherSANS,
+		IPSans:     req.IPSANS,
+		URISans:    req.URISANS,
+		TTL:        req.TTL,
+		Format:     req.Format,
+	}
+	if req.ExcludeCNFromSANS != nil {
+		signRequest.ExcludeCNFromSans = *req.ExcludeCNFromSANS
+	}
+	if signRequest.Format == "" {
+		signRequest.Format = "pem"
+	}
+	return c.Sign(logicalPath, roleName, signRequest, options...)
+}
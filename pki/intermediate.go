@@ -0,0 +1,117 @@
+package pki
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+)
+
+// ErrNotCACertificate is returned when a certificate that was expected to be
+// a CA (BasicConstraintsValid && IsCA) is not one
+var ErrNotCACertificate = fmt.Errorf("certificate is not a valid CA certificate")
+
+// SignIntermediate asks the PKI role under logicalPath to sign req as an
+// intermediate CA certificate (CA:TRUE, constrained by the role's
+// max_path_length). It validates that the returned certificate is actually a
+// CA before returning it.
+func (c *ServicesService) SignIntermediate(logicalPath, roleName string, req SignRequest, options ...OptionFunc) (*IssueResponse, *Response, error) {
+	if err := c.validate.Struct(req); err != nil {
+		return nil, nil, err
+	}
+	httpReq, err := c.client.newServiceRequest(http.MethodPost, "core/pki/api/"+logicalPath+"/sign-intermediate/"+roleName, &req, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	var responseStruct struct {
+		IssueResponse
+		ErrorResponse
+	}
+	resp, err := c.client.do(httpReq, &responseStruct)
+	if err != nil {
+		return nil, resp, err
+	}
+	if resp == nil {
+		return nil, resp, fmt.Errorf("SignIntermediate: %w", ErrEmptyResult)
+	}
+	issued := &responseStruct.IssueResponse
+	cert, err := issued.Data.GetCertificate()
+	if err != nil {
+		return issued, resp, fmt.Errorf("SignIntermediate: %w", err)
+	}
+	if !cert.BasicConstraintsValid || !cert.IsCA {
+		return issued, resp, ErrNotCACertificate
+	}
+	return issued, resp, nil
+}
+
+// crossSignRequest wraps the PEM of a foreign CA certificate. The PKI
+// service is expected to re-sign the Subject/SPKI it already contains under
+// its own root, without requiring a PKCS#10 proof-of-possession signature
+// for that key — the caller only has the public foreign certificate, not
+// its private key.
+type crossSignRequest struct {
+	Certificate string `json:"certificate" validate:"required"`
+	TTL         string `json:"ttl,omitempty"`
+}
+
+// CrossSign takes an existing external CA certificate and asks the PKI
+// service under logicalPath to issue a certificate for the same Subject/SPKI
+// under its own root. Unlike SignIntermediate, this does not go through a
+// role: the foreign CA's Subject was issued by a different root and is not
+// expected to satisfy any role's allowed_domains/policy constraints, so
+// binding the call to a role would defeat the cross-signing use case
+// (re-rooting an existing CA key without it first matching a local policy).
+func (c *ServicesService) CrossSign(logicalPath string, foreignCert *x509.Certificate, ttl string, options ...OptionFunc) (*IssueResponse, *Response, error) {
+	if !foreignCert.BasicConstraintsValid || !foreignCert.IsCA {
+		return nil, nil, ErrNotCACertificate
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: foreignCert.Raw})
+
+	req, err := c.client.newServiceRequest(http.MethodPost, "core/pki/api/"+logicalPath+"/cross-sign", &crossSignRequest{
+		Certificate: string(certPEM),
+		TTL:         ttl,
+	}, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	var responseStruct struct {
+		IssueResponse
+		ErrorResponse
+	}
+	resp, err := c.client.do(req, &responseStruct)
+	if err != nil {
+		return nil, resp, err
+	}
+	if resp == nil {
+		return nil, resp, fmt.Errorf("CrossSign: %w", ErrEmptyResult)
+	}
+	issued := &responseStruct.IssueResponse
+	cert, err := issued.Data.GetCertificate()
+	if err != nil {
+		return issued, resp, fmt.Errorf("CrossSign: %w", err)
+	}
+	if !cert.BasicConstraintsValid || !cert.IsCA {
+		return issued, resp, ErrNotCACertificate
+	}
+	return issued, resp, nil
+}
+
+// CAChain walks IssueData.CaChain, PEM-decoding and parsing each entry, so
+// callers can build a tls.Config trust pool without re-implementing PEM
+// decoding themselves.
+func (d *IssueData) CAChain() ([]*x509.Certificate, error) {
+	chain := make([]*x509.Certificate, 0, len(d.CaChain))
+	for _, entry := range d.CaChain {
+		block, _ := pem.Decode([]byte(entry))
+		if block == nil || block.Type != "CERTIFICATE" {
+			return nil, ErrCertificateExpected
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, cert)
+	}
+	return chain, nil
+}
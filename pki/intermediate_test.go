@@ -0,0 +1,87 @@
+package pki
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func selfSignedCAPEM(t *testing.T, commonName string) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestIssueDataCAChain(t *testing.T) {
+	data := IssueData{
+		CaChain: []string{
+			selfSignedCAPEM(t, "root-ca"),
+			selfSignedCAPEM(t, "intermediate-ca"),
+		},
+	}
+
+	chain, err := data.CAChain()
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.Len(t, chain, 2) {
+		return
+	}
+	assert.Equal(t, "root-ca", chain[0].Subject.CommonName)
+	assert.Equal(t, "intermediate-ca", chain[1].Subject.CommonName)
+}
+
+func TestIssueDataCAChainInvalidPEM(t *testing.T) {
+	data := IssueData{CaChain: []string{"not pem"}}
+	_, err := data.CAChain()
+	assert.Equal(t, ErrCertificateExpected, err)
+}
+
+func TestCrossSignRejectsNonCACert(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "leaf"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         false,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc := &ServicesService{}
+	_, _, err = svc.CrossSign("pki", leaf, "24h")
+	assert.Equal(t, ErrNotCACertificate, err)
+}
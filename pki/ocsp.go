@@ -0,0 +1,106 @@
+package pki
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// maxOCSPGetRequestSize is the largest DER-encoded OCSP request that will be
+// sent via the RFC 6960 Appendix A GET form before falling back to POST.
+// Staying well under common proxy/URL length limits keeps the base64 request
+// (which expands the DER size by ~4/3) safely inside a single URL.
+const maxOCSPGetRequestSize = 255
+
+// ocspGetPath builds the RFC 6960 Appendix A GET URL for an OCSP request:
+// the standard base64 encoding of derRequest, percent-escaped so that the
+// '/' characters base64 routinely produces don't get interpreted as extra
+// path segments by the server or any intermediary. '+' is left unescaped,
+// as url.PathEscape does not escape it in a path segment.
+func ocspGetPath(basePath string, derRequest []byte) string {
+	encoded := base64.StdEncoding.EncodeToString(derRequest)
+	return basePath + "/" + url.PathEscape(encoded)
+}
+
+// CheckOCSP builds an OCSP request for cert, signed by issuer, and asks the
+// PKI service's OCSP responder for its revocation status. It returns the
+// parsed, signature-verified OCSP response together with the raw response
+// bytes so callers can cache or re-verify them.
+func (c *ServicesService) CheckOCSP(cert, issuer *x509.Certificate, logicalPath string, options ...OptionFunc) (*ocsp.Response, []byte, *Response, error) {
+	ocspRequest, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("CheckOCSP: %w", err)
+	}
+
+	path := "core/pki/api/" + logicalPath + "/ocsp"
+
+	var req *http.Request
+	if len(ocspRequest) <= maxOCSPGetRequestSize {
+		req, err = c.client.newServiceRequest(http.MethodGet, ocspGetPath(path, ocspRequest), nil, options)
+	} else {
+		req, err = c.client.newServiceRequest(http.MethodPost, path, nil, options)
+		if err == nil {
+			req.Body = ioutil.NopCloser(bytes.NewReader(ocspRequest))
+			req.ContentLength = int64(len(ocspRequest))
+			req.Header.Set("Content-Type", "application/ocsp-request")
+		}
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	resp, err := c.client.do(req, nil)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+	if resp == nil {
+		return nil, nil, nil, fmt.Errorf("CheckOCSP: %w", ErrEmptyResult)
+	}
+	defer resp.Body.Close()
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, resp, err
+	}
+	parsed, err := ocsp.ParseResponseForCert(respBytes, cert, issuer)
+	if err != nil {
+		return nil, respBytes, resp, fmt.Errorf("CheckOCSP: %w", err)
+	}
+	return parsed, respBytes, resp, nil
+}
+
+// CheckOCSPBySerials resolves each of serials to a certificate via
+// GetCertificateBySerial and calls CheckOCSP against issuer for each one,
+// returning a map from serial number to OCSP response. It stops and returns
+// the partial map on the first lookup or OCSP failure.
+func (c *ServicesService) CheckOCSPBySerials(logicalPath string, serials []string, issuer *x509.Certificate, options ...OptionFunc) (map[string]*ocsp.Response, *Response, error) {
+	results := make(map[string]*ocsp.Response, len(serials))
+	var lastResp *Response
+	for _, serial := range serials {
+		issueResponse, resp, err := c.GetCertificateBySerial(logicalPath, serial, options...)
+		if resp != nil {
+			lastResp = resp
+		}
+		if err != nil {
+			return results, lastResp, fmt.Errorf("CheckOCSPBySerials: resolving %s: %w", serial, err)
+		}
+		cert, err := issueResponse.Data.GetCertificate()
+		if err != nil {
+			return results, lastResp, fmt.Errorf("CheckOCSPBySerials: parsing %s: %w", serial, err)
+		}
+		ocspResponse, _, resp, err := c.CheckOCSP(cert, issuer, logicalPath, options...)
+		if resp != nil {
+			lastResp = resp
+		}
+		if err != nil {
+			return results, lastResp, fmt.Errorf("CheckOCSPBySerials: checking %s: %w", serial, err)
+		}
+		results[serial] = ocspResponse
+	}
+	return results, lastResp, nil
+}
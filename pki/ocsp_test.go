@@ -0,0 +1,172 @@
+package pki
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ocsp"
+)
+
+func TestOCSPGetPathEscapesBase64Specials(t *testing.T) {
+	// Chosen so the standard base64 encoding contains both '+' and '/'.
+	derRequest := []byte{0xfb, 0xef, 0xbe, 0xff, 0xff, 0xbe}
+
+	path := ocspGetPath("core/pki/api/foo/ocsp", derRequest)
+
+	segments := strings.Split(strings.TrimPrefix(path, "core/pki/api/foo/ocsp/"), "/")
+	assert.Equal(t, 1, len(segments), "the base64 request must not be split into extra path segments")
+}
+
+func generateTestCA(t *testing.T, commonName string, serial int64) (*ecdsa.PrivateKey, *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, cert
+}
+
+func generateTestLeaf(t *testing.T, issuerKey *ecdsa.PrivateKey, issuerCert *x509.Certificate, commonName string, serial int64) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, issuerCert, &key.PublicKey, issuerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+// TestCheckOCSPRoundTrip exercises CheckOCSP end to end against a mock PKI
+// server, following this repo's setup/mux test pattern (see
+// cartel/create_test.go). It also checks the escaping of the GET form used
+// for small requests: the captured (still escaped) request path must not
+// contain a raw '/' inside the base64 segment.
+func TestCheckOCSPRoundTrip(t *testing.T) {
+	teardown, err := setup(t, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	issuerKey, issuerCert := generateTestCA(t, "ocsp-test-root", 1)
+	leaf := generateTestLeaf(t, issuerKey, issuerCert, "leaf.example.com", 2)
+
+	ocspResponse, err := ocsp.CreateResponse(issuerCert, issuerCert, ocsp.Response{
+		SerialNumber: leaf.SerialNumber,
+		Status:       ocsp.Good,
+		ThisUpdate:   time.Now().Add(-time.Minute),
+		NextUpdate:   time.Now().Add(time.Hour),
+	}, issuerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var capturedPath string
+	muxPKI.HandleFunc("/core/pki/api/pki/ocsp/", func(w http.ResponseWriter, r *http.Request) {
+		capturedPath = r.URL.EscapedPath()
+		w.Header().Set("Content-Type", "application/ocsp-response")
+		_, _ = w.Write(ocspResponse)
+	})
+
+	svc := &ServicesService{client: client, validate: validator.New()}
+	parsed, raw, resp, err := svc.CheckOCSP(leaf, issuerCert, "pki")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotNil(t, resp)
+	assert.NotEmpty(t, raw)
+	assert.Equal(t, ocsp.Good, parsed.Status)
+
+	segment := strings.TrimPrefix(capturedPath, "/core/pki/api/pki/ocsp/")
+	assert.NotContains(t, segment, "/")
+}
+
+// TestCheckOCSPBySerialsRoundTrip exercises the serial-resolution plus OCSP
+// lookup path together: GetCertificateBySerial resolves the certificate,
+// then CheckOCSP is called against it.
+func TestCheckOCSPBySerialsRoundTrip(t *testing.T) {
+	teardown, err := setup(t, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	issuerKey, issuerCert := generateTestCA(t, "ocsp-test-root-2", 1)
+	leaf := generateTestLeaf(t, issuerKey, issuerCert, "leaf2.example.com", 7)
+	leafPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw}))
+
+	ocspResponse, err := ocsp.CreateResponse(issuerCert, issuerCert, ocsp.Response{
+		SerialNumber: leaf.SerialNumber,
+		Status:       ocsp.Good,
+		ThisUpdate:   time.Now().Add(-time.Minute),
+		NextUpdate:   time.Now().Add(time.Hour),
+	}, issuerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	muxPKI.HandleFunc("/core/pki/api/pki/cert/07", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		body, _ := json.Marshal(map[string]interface{}{
+			"request_id": "r", "lease_id": "l", "renewable": true, "lease_duration": 0,
+			"data": map[string]interface{}{"certificate": leafPEM},
+		})
+		_, _ = w.Write(body)
+	})
+	muxPKI.HandleFunc("/core/pki/api/pki/ocsp/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/ocsp-response")
+		_, _ = w.Write(ocspResponse)
+	})
+
+	svc := &ServicesService{client: client, validate: validator.New()}
+	results, resp, err := svc.CheckOCSPBySerials("pki", []string{"07"}, issuerCert)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotNil(t, resp)
+	if !assert.Contains(t, results, "07") {
+		return
+	}
+	assert.Equal(t, ocsp.Good, results["07"].Status)
+}
@@ -0,0 +1,206 @@
+package pki
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// DefaultRenewThreshold is the fraction of a certificate's total lifetime
+// remaining at which Renewer will attempt to re-issue it.
+const DefaultRenewThreshold = 1.0 / 3.0
+
+// RenewerOptions configures the renewal behaviour of a Renewer
+type RenewerOptions struct {
+	// RenewThreshold is the fraction (0-1) of the certificate's total
+	// lifetime remaining at which a renewal is attempted. Defaults to
+	// DefaultRenewThreshold when zero.
+	RenewThreshold float64
+	// Jitter is the maximum random delay added to the computed renewal
+	// time, to avoid a thundering herd of renewals. Defaults to 1 minute.
+	Jitter time.Duration
+	// MinBackoff is the initial delay before retrying a failed renewal.
+	// Defaults to 5 seconds.
+	MinBackoff time.Duration
+	// MaxBackoff caps the exponential backoff delay between retries.
+	// Defaults to 5 minutes.
+	MaxBackoff time.Duration
+	// CheckInterval bounds how often Renewer re-evaluates the current
+	// lease when it is not yet due for renewal. Defaults to 30 seconds.
+	CheckInterval time.Duration
+}
+
+func (o RenewerOptions) withDefaults() RenewerOptions {
+	if o.RenewThreshold <= 0 {
+		o.RenewThreshold = DefaultRenewThreshold
+	}
+	if o.Jitter <= 0 {
+		o.Jitter = time.Minute
+	}
+	if o.MinBackoff <= 0 {
+		o.MinBackoff = 5 * time.Second
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = 5 * time.Minute
+	}
+	if o.CheckInterval <= 0 {
+		o.CheckInterval = 30 * time.Second
+	}
+	return o
+}
+
+// Renewer periodically re-issues a certificate in a background goroutine,
+// swapping in the new IssueResponse once the remaining lease time drops
+// below RenewerOptions.RenewThreshold, so a long-running process can read
+// Current() for up-to-date TLS material without restarting.
+type Renewer struct {
+	opts RenewerOptions
+
+	// issue performs a single (re-)issuance. It is set by NewRenewer to call
+	// svc.IssueCertificate(logicalPath, roleName, req); tests in this
+	// package substitute a fake to drive Start/run without a live service.
+	issue func() (*IssueResponse, *Response, error)
+
+	mu      sync.RWMutex
+	current *IssueResponse
+
+	renewedFunc func(*IssueResponse)
+	errCh       chan error
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewRenewer creates a Renewer for the given role and certificate request.
+// Start must be called to begin issuing and renewing the certificate.
+func NewRenewer(svc *ServicesService, logicalPath, roleName string, req CertificateRequest, opts RenewerOptions) *Renewer {
+	return &Renewer{
+		issue: func() (*IssueResponse, *Response, error) {
+			return svc.IssueCertificate(logicalPath, roleName, req)
+		},
+		opts:  opts.withDefaults(),
+		errCh: make(chan error, 1),
+	}
+}
+
+// Renewed registers a callback invoked every time a new certificate is
+// issued, including the initial issuance performed by Start. Only one
+// callback can be registered at a time.
+func (r *Renewer) Renewed(fn func(*IssueResponse)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.renewedFunc = fn
+}
+
+// Errors returns a channel on which renewal failures are reported. The
+// channel has a buffer of one: callers that do not read it will only see
+// the most recent error.
+func (r *Renewer) Errors() <-chan error {
+	return r.errCh
+}
+
+// Current returns the most recently issued certificate, or nil if Start has
+// not yet completed an initial issuance. It is safe to call concurrently
+// with a running renewal loop.
+func (r *Renewer) Current() *IssueResponse {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.current
+}
+
+// Start issues the initial certificate and launches the background renewal
+// goroutine. The goroutine stops when ctx is cancelled or Stop is called.
+func (r *Renewer) Start(ctx context.Context) error {
+	issued, _, err := r.issue()
+	if err != nil {
+		return err
+	}
+	r.setCurrent(issued)
+
+	ctx, cancel := context.WithCancel(ctx)
+	r.cancel = cancel
+	r.done = make(chan struct{})
+	go r.run(ctx)
+	return nil
+}
+
+// Stop terminates the background renewal goroutine. It is safe to call Stop
+// more than once.
+func (r *Renewer) Stop() {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+	<-r.done
+}
+
+func (r *Renewer) setCurrent(issued *IssueResponse) {
+	r.mu.Lock()
+	r.current = issued
+	fn := r.renewedFunc
+	r.mu.Unlock()
+	if fn != nil {
+		fn(issued)
+	}
+}
+
+func (r *Renewer) run(ctx context.Context) {
+	defer close(r.done)
+	backoff := r.opts.MinBackoff
+	for {
+		wait := r.nextCheck()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		if !r.dueForRenewal() {
+			continue
+		}
+		issued, _, err := r.issue()
+		if err != nil {
+			select {
+			case r.errCh <- err:
+			default:
+				<-r.errCh
+				r.errCh <- err
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > r.opts.MaxBackoff {
+				backoff = r.opts.MaxBackoff
+			}
+			continue
+		}
+		backoff = r.opts.MinBackoff
+		r.setCurrent(issued)
+	}
+}
+
+// nextCheck returns how long to sleep before re-evaluating the current
+// lease, capped at CheckInterval so renewal due-times are never missed by
+// more than that amount.
+func (r *Renewer) nextCheck() time.Duration {
+	return r.opts.CheckInterval
+}
+
+func (r *Renewer) dueForRenewal() bool {
+	current := r.Current()
+	if current == nil {
+		return true
+	}
+	total := time.Duration(current.LeaseDuration) * time.Second
+	if total <= 0 {
+		return false
+	}
+	expiration := time.Unix(int64(current.Data.Expiration), 0)
+	remaining := time.Until(expiration)
+	threshold := time.Duration(float64(total) * r.opts.RenewThreshold)
+	jitter := time.Duration(rand.Int63n(int64(r.opts.Jitter) + 1))
+	return remaining <= threshold+jitter
+}
@@ -0,0 +1,201 @@
+package pki
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeIssuer lets tests drive Renewer.Start/run without a live
+// ServicesService: it counts calls and can be told to fail for a number of
+// leading calls before succeeding, exercising the backoff path.
+type fakeIssuer struct {
+	mu        sync.Mutex
+	calls     int
+	failUntil int
+	leaseSecs int
+}
+
+func (f *fakeIssuer) issue() (*IssueResponse, *Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.calls <= f.failUntil {
+		return nil, nil, fmt.Errorf("fakeIssuer: simulated failure %d", f.calls)
+	}
+	return &IssueResponse{
+		RequestID:     fmt.Sprintf("req-%d", f.calls),
+		LeaseDuration: f.leaseSecs,
+		Data: IssueData{
+			Expiration: int(time.Now().Add(time.Duration(f.leaseSecs) * time.Second).Unix()),
+		},
+	}, nil, nil
+}
+
+func (f *fakeIssuer) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+func newTestRenewer(issuer *fakeIssuer, opts RenewerOptions) *Renewer {
+	return &Renewer{
+		issue: issuer.issue,
+		opts:  opts.withDefaults(),
+		errCh: make(chan error, 1),
+	}
+}
+
+func TestRenewerOptionsWithDefaults(t *testing.T) {
+	opts := RenewerOptions{}.withDefaults()
+	assert.Equal(t, DefaultRenewThreshold, opts.RenewThreshold)
+	assert.Equal(t, time.Minute, opts.Jitter)
+	assert.Equal(t, 5*time.Second, opts.MinBackoff)
+	assert.Equal(t, 5*time.Minute, opts.MaxBackoff)
+	assert.Equal(t, 30*time.Second, opts.CheckInterval)
+
+	custom := RenewerOptions{RenewThreshold: 0.5}.withDefaults()
+	assert.Equal(t, 0.5, custom.RenewThreshold)
+	assert.Equal(t, time.Minute, custom.Jitter)
+}
+
+func TestRenewerDueForRenewalNoCurrent(t *testing.T) {
+	r := NewRenewer(nil, "pki", "role", CertificateRequest{}, RenewerOptions{})
+	assert.True(t, r.dueForRenewal(), "a Renewer with no issued certificate yet is always due")
+}
+
+func TestRenewerDueForRenewalThreshold(t *testing.T) {
+	r := NewRenewer(nil, "pki", "role", CertificateRequest{}, RenewerOptions{
+		RenewThreshold: 1.0 / 3.0,
+		Jitter:         time.Nanosecond, // zero would default to one minute
+	})
+
+	total := 90 * time.Second
+	r.setCurrent(&IssueResponse{
+		LeaseDuration: int(total.Seconds()),
+		Data: IssueData{
+			Expiration: int(time.Now().Add(40 * time.Second).Unix()),
+		},
+	})
+	assert.False(t, r.dueForRenewal(), "remaining lifetime is above the 1/3 threshold")
+
+	r.setCurrent(&IssueResponse{
+		LeaseDuration: int(total.Seconds()),
+		Data: IssueData{
+			Expiration: int(time.Now().Add(20 * time.Second).Unix()),
+		},
+	})
+	assert.True(t, r.dueForRenewal(), "remaining lifetime is below the 1/3 threshold")
+}
+
+func TestRenewerDueForRenewalZeroLeaseDuration(t *testing.T) {
+	r := NewRenewer(nil, "pki", "role", CertificateRequest{}, RenewerOptions{})
+	r.setCurrent(&IssueResponse{
+		LeaseDuration: 0,
+		Data: IssueData{
+			Expiration: int(time.Now().Add(-time.Second).Unix()),
+		},
+	})
+	assert.False(t, r.dueForRenewal(), "a certificate with no known lease duration should not be treated as due")
+}
+
+func TestRenewerCurrentAndRenewedCallback(t *testing.T) {
+	r := NewRenewer(nil, "pki", "role", CertificateRequest{}, RenewerOptions{})
+
+	var mu sync.Mutex
+	var seen []*IssueResponse
+	r.Renewed(func(issued *IssueResponse) {
+		mu.Lock()
+		defer mu.Unlock()
+		seen = append(seen, issued)
+	})
+
+	first := &IssueResponse{RequestID: "1"}
+	second := &IssueResponse{RequestID: "2"}
+	r.setCurrent(first)
+	r.setCurrent(second)
+
+	assert.Equal(t, second, r.Current())
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []*IssueResponse{first, second}, seen)
+}
+
+func TestRenewerStartReturnsInitialIssueError(t *testing.T) {
+	issuer := &fakeIssuer{failUntil: 1, leaseSecs: 3600}
+	r := newTestRenewer(issuer, RenewerOptions{})
+
+	err := r.Start(context.Background())
+	assert.Error(t, err)
+	assert.Nil(t, r.Current())
+}
+
+func TestRenewerStartRenewsInBackground(t *testing.T) {
+	issuer := &fakeIssuer{leaseSecs: 1}
+	r := newTestRenewer(issuer, RenewerOptions{
+		RenewThreshold: 1, // every lease is immediately due, forcing repeated renewals
+		CheckInterval:  5 * time.Millisecond,
+		Jitter:         time.Millisecond,
+	})
+
+	err := r.Start(context.Background())
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer r.Stop()
+
+	assert.Eventually(t, func() bool {
+		return issuer.callCount() >= 3
+	}, 500*time.Millisecond, 5*time.Millisecond, "renewal loop should have re-issued the certificate multiple times")
+
+	assert.NotNil(t, r.Current())
+}
+
+func TestRenewerStopTerminatesRunLoop(t *testing.T) {
+	issuer := &fakeIssuer{leaseSecs: 1}
+	r := newTestRenewer(issuer, RenewerOptions{
+		RenewThreshold: 1,
+		CheckInterval:  5 * time.Millisecond,
+	})
+
+	if err := r.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	r.Stop()
+
+	countAtStop := issuer.callCount()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, countAtStop, issuer.callCount(), "no further renewals should happen after Stop")
+}
+
+func TestRenewerBackoffOnRenewalFailure(t *testing.T) {
+	issuer := &fakeIssuer{leaseSecs: 1, failUntil: 0}
+	r := newTestRenewer(issuer, RenewerOptions{
+		RenewThreshold: 1,
+		CheckInterval:  5 * time.Millisecond,
+		MinBackoff:     5 * time.Millisecond,
+		MaxBackoff:     20 * time.Millisecond,
+	})
+
+	if err := r.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Stop()
+
+	// Force every subsequent renewal to fail and assert the failure surfaces
+	// on Errors() instead of silently stalling the loop.
+	issuer.mu.Lock()
+	issuer.failUntil = issuer.calls + 1000
+	issuer.mu.Unlock()
+
+	select {
+	case err := <-r.Errors():
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("expected a renewal error on Errors()")
+	}
+}